@@ -0,0 +1,100 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filepath
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+)
+
+// File is the subset of *os.File that a FS implementation's Open
+// must return, enough for a caller walking the tree to read a
+// visited file's contents.
+type File interface {
+	Read(b []byte) (n int, err os.Error)
+	Close() os.Error
+}
+
+// FS abstracts a directory hierarchy so that WalkFS can walk
+// something other than the real filesystem: a real directory (see
+// DirFS), an in-memory fixture built for a test, or a reader over a
+// tar or zip archive. Every name FS sees is a slash-separated path,
+// as in the sibling path package, regardless of the host OS.
+type FS interface {
+	Open(name string) (File, os.Error)
+	ReadDir(name string) ([]*os.FileInfo, os.Error)
+	Stat(name string) (*os.FileInfo, os.Error)
+}
+
+// DirFS implements FS over the real directory tree rooted at the
+// string value, translating the slash paths FS methods receive into
+// native OS paths via FromSlash.
+type DirFS string
+
+func (dir DirFS) resolve(name string) string {
+	return Join(string(dir), FromSlash(name))
+}
+
+func (dir DirFS) Open(name string) (File, os.Error) {
+	return os.Open(dir.resolve(name))
+}
+
+func (dir DirFS) ReadDir(name string) ([]*os.FileInfo, os.Error) {
+	return ioutil.ReadDir(dir.resolve(name))
+}
+
+func (dir DirFS) Stat(name string) (*os.FileInfo, os.Error) {
+	return os.Lstat(dir.resolve(name))
+}
+
+func walkFS(fsys FS, name string, info *os.FileInfo, walkFn WalkFunc) os.Error {
+	err := walkFn(name, info, nil)
+	if !info.IsDirectory() {
+		return err
+	}
+	if err != nil {
+		if err == SkipDir {
+			return nil
+		}
+		return err
+	}
+
+	list, err := fsys.ReadDir(name)
+	if err != nil {
+		return walkFn(name, info, err)
+	}
+	sort.Sort(byName(list))
+
+	for _, fileInfo := range list {
+		filename := path.Join(name, fileInfo.Name)
+		if err = walkFS(fsys, filename, fileInfo, walkFn); err != nil {
+			if !fileInfo.IsDirectory() || err != SkipDir {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WalkFS walks the file tree rooted at root within fsys, calling
+// walkFn for each file or directory in the tree, including root,
+// exactly as Walk does for the real filesystem. Every path passed to
+// walkFn is joined with '/' via the path package, regardless of the
+// host OS, since fsys may not represent real OS paths at all (an
+// archive member name, for instance).
+func WalkFS(fsys FS, root string, walkFn WalkFunc) os.Error {
+	info, err := fsys.Stat(root)
+	if err != nil {
+		err = walkFn(root, nil, err)
+	} else {
+		err = walkFS(fsys, root, info, walkFn)
+	}
+	if err == SkipDir {
+		return nil
+	}
+	return err
+}
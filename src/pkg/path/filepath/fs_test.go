@@ -0,0 +1,70 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filepath_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWalkFS(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "TestWalkFS")
+	if err != nil {
+		t.Fatal("TempDir: ", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	makeWalkTree(t, tmpDir)
+
+	var visited []string
+	err = filepath.WalkFS(filepath.DirFS(tmpDir), "", func(path string, info *os.FileInfo, err os.Error) os.Error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal("WalkFS: ", err)
+	}
+
+	want := []string{"", "a", "b", "b/c", "skip", "skip/d"}
+	if len(visited) != len(want) {
+		t.Fatalf("WalkFS visited %v, want %v", visited, want)
+	}
+	for i, w := range want {
+		if visited[i] != w {
+			t.Errorf("WalkFS visited[%d] = %q, want %q (visited=%v)", i, visited[i], w, visited)
+		}
+	}
+}
+
+func TestDirFSOpen(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "TestDirFSOpen")
+	if err != nil {
+		t.Fatal("TempDir: ", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	want := []byte("hello")
+	if err := ioutil.WriteFile(filepath.Join(tmpDir, "a"), want, 0600); err != nil {
+		t.Fatal("WriteFile: ", err)
+	}
+
+	f, err := filepath.DirFS(tmpDir).Open("a")
+	if err != nil {
+		t.Fatal("Open: ", err)
+	}
+	defer f.Close()
+
+	got := make([]byte, len(want))
+	if _, err := f.Read(got); err != nil {
+		t.Fatal("Read: ", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Open(%q) read %q, want %q", "a", got, want)
+	}
+}
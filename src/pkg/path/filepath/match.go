@@ -0,0 +1,343 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filepath
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrBadPattern indicates a pattern was malformed.
+var ErrBadPattern = os.NewError("syntax error in pattern")
+
+// Match reports whether name matches the shell file name pattern.
+// The pattern syntax is:
+//
+//	pattern:
+//		{ term }
+//	term:
+//		'*'         matches any sequence of non-Separator characters
+//		'?'         matches any single non-Separator character
+//		'[' [ '^' ] { character-range } ']'
+//		            character class (must be non-empty)
+//		c           matches character c (c != '*', '?', '\\', '[')
+//		'\\' c      matches character c
+//
+//	character-range:
+//		c           matches character c (c != '\\', '-', ']')
+//		'\\' c      matches character c
+//		lo '-' hi   matches character c for lo <= c <= hi
+//
+// Match requires pattern to match all of name, not just a substring.
+// The only possible returned error is ErrBadPattern, when pattern
+// is malformed.
+//
+// Beyond the standard shell syntax, a pattern element consisting
+// solely of "**" is a documented extension: it matches zero or more
+// whole path elements, crossing Separator boundaries freely. For
+// example, "a/**/z" matches "a/z", "a/b/z" and "a/b/c/z" (assuming
+// the Separator is '/').
+// On non-Windows systems, '\\' escapes the next character.
+func Match(pattern, name string) (matched bool, err os.Error) {
+	if strings.Contains(pattern, "**") {
+		return matchSegments(splitSeparator(pattern), splitSeparator(name))
+	}
+	return match(pattern, name)
+}
+
+// splitSeparator splits path into the elements between Separator,
+// the building blocks matchSegments walks when a pattern uses the
+// "**" extension.
+func splitSeparator(path string) []string {
+	return strings.Split(path, string(Separator))
+}
+
+// matchSegments matches a pattern already split into path elements
+// against a name split the same way, expanding any "**" element into
+// zero or more name elements before resuming the rest of the
+// pattern.
+func matchSegments(patSegs, nameSegs []string) (matched bool, err os.Error) {
+	if len(patSegs) == 0 {
+		return len(nameSegs) == 0, nil
+	}
+	if patSegs[0] == "**" {
+		// Try consuming zero name elements first, then one, then
+		// two, and so on, until either the rest of the pattern
+		// matches or the name runs out.
+		if matched, err = matchSegments(patSegs[1:], nameSegs); matched || err != nil {
+			return matched, err
+		}
+		if len(nameSegs) == 0 {
+			return false, nil
+		}
+		return matchSegments(patSegs, nameSegs[1:])
+	}
+	if len(nameSegs) == 0 {
+		return false, nil
+	}
+	ok, err := match(patSegs[0], nameSegs[0])
+	if err != nil || !ok {
+		return false, err
+	}
+	return matchSegments(patSegs[1:], nameSegs[1:])
+}
+
+// match implements the standard (non-doublestar) shell pattern
+// matching that Match documents.
+func match(pattern, name string) (matched bool, err os.Error) {
+Pattern:
+	for len(pattern) > 0 {
+		var star bool
+		var chunk string
+		star, chunk, pattern = scanChunk(pattern)
+		if star && chunk == "" {
+			// Trailing * matches rest of string unless it has a separator.
+			return !strings.Contains(name, string(Separator)), nil
+		}
+		// Look for match at current position.
+		t, ok, err := matchChunk(chunk, name)
+		// If we're the last chunk, make sure we've exhausted the name,
+		// otherwise we'd give a false result even though a trailing
+		// star could still match.
+		if ok && (len(t) == 0 || len(pattern) > 0) {
+			name = t
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if star {
+			// Look for match skipping i+1 bytes.
+			for i := 0; i < len(name) && name[i] != Separator; i++ {
+				t, ok, err := matchChunk(chunk, name[i+1:])
+				if ok {
+					// If we're the last chunk, make sure we exhausted the name.
+					if len(pattern) == 0 && len(t) > 0 {
+						continue
+					}
+					name = t
+					continue Pattern
+				}
+				if err != nil {
+					return false, err
+				}
+			}
+		}
+		return false, nil
+	}
+	return len(name) == 0, nil
+}
+
+// scanChunk gets the next section of pattern, which is a non-star
+// string possibly preceded by a star.
+func scanChunk(pattern string) (star bool, chunk, rest string) {
+	for len(pattern) > 0 && pattern[0] == '*' {
+		pattern = pattern[1:]
+		star = true
+	}
+	inrange := false
+	var i int
+Scan:
+	for i = 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if runtime.GOOS != "windows" {
+				// error check handled in matchChunk: consume next byte
+				if i+1 < len(pattern) {
+					i++
+				}
+			}
+		case '[':
+			inrange = true
+		case ']':
+			inrange = false
+		case '*':
+			if !inrange {
+				break Scan
+			}
+		}
+	}
+	return star, pattern[0:i], pattern[i:]
+}
+
+// matchChunk checks whether chunk matches the beginning of s.
+// If so, it returns the remainder of s (after the match).
+func matchChunk(chunk, s string) (rest string, ok bool, err os.Error) {
+	for len(chunk) > 0 {
+		if len(s) == 0 {
+			return
+		}
+		switch chunk[0] {
+		case '[':
+			// character class
+			r, n := utf8.DecodeRuneInString(s)
+			s = s[n:]
+			chunk = chunk[1:]
+			// possibly negated
+			negated := false
+			if len(chunk) > 0 && (chunk[0] == '^' || chunk[0] == '!') {
+				negated = true
+				chunk = chunk[1:]
+			}
+			// parse all ranges
+			match := false
+			nrange := 0
+			for {
+				if len(chunk) > 0 && chunk[0] == ']' && nrange > 0 {
+					chunk = chunk[1:]
+					break
+				}
+				var lo, hi int
+				if lo, chunk, err = getEsc(chunk); err != nil {
+					return
+				}
+				hi = lo
+				if len(chunk) > 0 && chunk[0] == '-' {
+					if hi, chunk, err = getEsc(chunk[1:]); err != nil {
+						return
+					}
+				}
+				if lo <= int(r) && int(r) <= hi {
+					match = true
+				}
+				nrange++
+			}
+			if match == negated {
+				return
+			}
+
+		case '?':
+			if s[0] == Separator {
+				return
+			}
+			_, n := utf8.DecodeRuneInString(s)
+			s = s[n:]
+			chunk = chunk[1:]
+
+		case '\\':
+			if runtime.GOOS != "windows" {
+				chunk = chunk[1:]
+				if len(chunk) == 0 {
+					err = ErrBadPattern
+					return
+				}
+			}
+			fallthrough
+
+		default:
+			if chunk[0] != s[0] {
+				return
+			}
+			s = s[1:]
+			chunk = chunk[1:]
+		}
+	}
+	return s, true, nil
+}
+
+// getEsc gets a possibly-escaped character from the beginning of chunk.
+func getEsc(chunk string) (r int, nchunk string, err os.Error) {
+	if len(chunk) == 0 || chunk[0] == '-' || chunk[0] == ']' {
+		err = ErrBadPattern
+		return
+	}
+	if chunk[0] == '\\' && runtime.GOOS != "windows" {
+		chunk = chunk[1:]
+		if len(chunk) == 0 {
+			err = ErrBadPattern
+			return
+		}
+	}
+	rr, n := utf8.DecodeRuneInString(chunk)
+	r = int(rr)
+	nchunk = chunk[n:]
+	if len(nchunk) == 0 {
+		err = ErrBadPattern
+	}
+	return
+}
+
+// hasMeta reports whether path contains any of the magic characters
+// recognized by Match.
+func hasMeta(path string) bool {
+	return strings.IndexAny(path, "*?[") >= 0
+}
+
+// BUG(niemeyer): Glob does not implement the ** extension that Match does;
+// patterns containing ** only work when passed directly to Match.
+
+// Glob returns the names of all files matching pattern or nil if
+// there is no matching file. The syntax of patterns is the same as
+// in Match. The pattern may describe hierarchical names such as
+// /usr/*/bin/ed (assuming the Separator is '/').
+//
+// The only possible returned error is ErrBadPattern, when pattern
+// is malformed.
+func Glob(pattern string) (matches []string, err os.Error) {
+	if !hasMeta(pattern) {
+		if _, err = os.Lstat(pattern); err != nil {
+			return nil, nil
+		}
+		return []string{pattern}, nil
+	}
+
+	dir, file := Split(pattern)
+	switch dir {
+	case "":
+		dir = "."
+	case string(Separator):
+		// nothing to trim
+	default:
+		dir = dir[0 : len(dir)-1] // chop off trailing separator
+	}
+
+	if !hasMeta(dir) {
+		return glob(dir, file, nil)
+	}
+
+	var m []string
+	m, err = Glob(dir)
+	if err != nil {
+		return
+	}
+	for _, d := range m {
+		matches, err = glob(d, file, matches)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// glob searches for files matching pattern in the directory dir
+// and appends them to matches.
+func glob(dir, pattern string, matches []string) (m []string, e os.Error) {
+	m = matches
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return
+	}
+	if !fi.IsDirectory() {
+		return
+	}
+	list, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, n := range list {
+		matched, err := Match(pattern, n.Name)
+		if err != nil {
+			return m, err
+		}
+		if matched {
+			m = append(m, Join(dir, n.Name))
+		}
+	}
+	return
+}
@@ -0,0 +1,183 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filepath_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type MatchTest struct {
+	pattern, s string
+	match      bool
+	err        os.Error
+}
+
+var matchTests = []MatchTest{
+	{"abc", "abc", true, nil},
+	{"*", "abc", true, nil},
+	{"*c", "abc", true, nil},
+	{"a*", "a", true, nil},
+	{"a*", "abc", true, nil},
+	{"a*", "ab/c", false, nil},
+	{"a*/b", "abc/b", true, nil},
+	{"a*/b", "a/c/b", false, nil},
+	{"a*b*c*d*e*/f", "axbxcxdxe/f", true, nil},
+	{"a*b*c*d*e*/f", "axbxcxdxexxx/f", true, nil},
+	{"a*b*c*d*e*/f", "axbxcxdxe/xxx/f", false, nil},
+	{"a*b*c*d*e*/f", "axbxcxdxexxx/fff", false, nil},
+	{"a*b?c*x", "abxbbxdbxebxczzx", true, nil},
+	{"a*b?c*x", "abxbbxdbxebxczzy", false, nil},
+	{"ab[c]", "abc", true, nil},
+	{"ab[b-d]", "abc", true, nil},
+	{"ab[e-g]", "abc", false, nil},
+	{"ab[^c]", "abc", false, nil},
+	{"ab[^b-d]", "abc", false, nil},
+	{"ab[^e-g]", "abc", true, nil},
+	{"a\\*b", "a*b", true, nil},
+	{"a\\*b", "ab", false, nil},
+	{"a?b", "a☺b", true, nil},
+	{"a[^a]b", "a☺b", true, nil},
+	{"a???b", "a☺b", false, nil},
+	{"a[^a][^a][^a]b", "a☺b", false, nil},
+	{"[a-ζ]*", "α", true, nil},
+	{"*[a-ζ]", "A", false, nil},
+	{"a?b", "a/b", false, nil},
+	{"a*b", "a/b", false, nil},
+	{"[\\]a]", "]", true, nil},
+	{"[\\-]", "-", true, nil},
+	{"[x\\-]", "x", true, nil},
+	{"[x\\-]", "-", true, nil},
+	{"[x\\-]", "z", false, nil},
+	{"[\\-x]", "x", true, nil},
+	{"[\\-x]", "-", true, nil},
+	{"[\\-x]", "a", false, nil},
+	{"[]a]", "]", false, filepath.ErrBadPattern},
+	{"[-]", "-", false, filepath.ErrBadPattern},
+	{"[x-]", "x", false, filepath.ErrBadPattern},
+	{"[x-]", "-", false, filepath.ErrBadPattern},
+	{"[x-]", "z", false, filepath.ErrBadPattern},
+	{"[-x]", "x", false, filepath.ErrBadPattern},
+	{"[-x]", "-", false, filepath.ErrBadPattern},
+	{"[-x]", "a", false, filepath.ErrBadPattern},
+	{"\\", "a", false, filepath.ErrBadPattern},
+	{"[a-b-c]", "a", false, filepath.ErrBadPattern},
+	{"[", "a", false, filepath.ErrBadPattern},
+	{"[^", "a", false, filepath.ErrBadPattern},
+	{"[^bc", "a", false, filepath.ErrBadPattern},
+	{"a[", "a", false, filepath.ErrBadPattern},
+	{"a[", "ab", false, filepath.ErrBadPattern},
+	{"*x", "xxx", true, nil},
+}
+
+func TestMatch(t *testing.T) {
+	for _, tt := range matchTests {
+		pattern := tt.pattern
+		s := tt.s
+		if runtime.GOOS == "windows" {
+			if strings.Contains(pattern, "\\") {
+				// no escape, so we can not use it, fmt it simply
+				continue
+			}
+		}
+		ok, err := filepath.Match(pattern, s)
+		if ok != tt.match || err != tt.err {
+			t.Errorf("Match(%#q, %#q) = %v, %q want %v, %q", pattern, s, ok, err, tt.match, tt.err)
+		}
+	}
+}
+
+type DoubleStarTest struct {
+	pattern, s string
+	match      bool
+}
+
+var doubleStarTests = []DoubleStarTest{
+	{"a/**/z", "a/z", true},
+	{"a/**/z", "a/b/z", true},
+	{"a/**/z", "a/b/c/z", true},
+	{"a/**/z", "a/b/c/y", false},
+	{"**/z", "z", true},
+	{"**/z", "a/b/z", true},
+	{"a/**", "a", true},
+	{"a/**", "a/b/c", true},
+	{"a/**/b/*.go", "a/x/y/b/foo.go", true},
+	{"a/**/b/*.go", "a/x/y/b/foo.txt", false},
+}
+
+func TestMatchDoubleStar(t *testing.T) {
+	for _, tt := range doubleStarTests {
+		// The table is written with '/' as the separator.
+		tt.pattern = filepath.FromSlash(tt.pattern)
+		tt.s = filepath.FromSlash(tt.s)
+		ok, err := filepath.Match(tt.pattern, tt.s)
+		if err != nil {
+			t.Errorf("Match(%#q, %#q) returned error %v", tt.pattern, tt.s, err)
+			continue
+		}
+		if ok != tt.match {
+			t.Errorf("Match(%#q, %#q) = %v, want %v", tt.pattern, tt.s, ok, tt.match)
+		}
+	}
+}
+
+func TestGlob(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "TestGlob")
+	if err != nil {
+		t.Fatal("TempDir: ", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for _, name := range []string{"a/x.txt", "a/y.txt", "b/x.txt"} {
+		dir := filepath.Join(tmpDir, filepath.Dir(name))
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal("MkdirAll: ", err)
+		}
+		f, err := os.Create(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatal("Create: ", err)
+		}
+		f.Close()
+	}
+
+	matches, err := filepath.Glob(filepath.Join(tmpDir, "*", "x.txt"))
+	if err != nil {
+		t.Fatalf("Glob returned error %v", err)
+	}
+	sort.Strings(matches)
+	want := []string{
+		filepath.Join(tmpDir, "a", "x.txt"),
+		filepath.Join(tmpDir, "b", "x.txt"),
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("Glob = %v, want %v", matches, want)
+	}
+	for i := range want {
+		if matches[i] != want[i] {
+			t.Errorf("Glob[%d] = %q, want %q", i, matches[i], want[i])
+		}
+	}
+
+	// A pattern with meta characters but no matching files returns
+	// nil matches and no error.
+	matches, err = filepath.Glob(filepath.Join(tmpDir, "nomatch*"))
+	if err != nil {
+		t.Fatalf("Glob returned error %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("Glob = %v, want no matches", matches)
+	}
+
+	// A malformed pattern reports ErrBadPattern.
+	_, err = filepath.Glob(filepath.Join(tmpDir, "["))
+	if err != filepath.ErrBadPattern {
+		t.Errorf("Glob with bad pattern returned error %v, want %v", err, filepath.ErrBadPattern)
+	}
+}
@@ -10,10 +10,48 @@ package filepath
 import (
 	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 )
 
-// BUG(niemeyer): Windows support is missing in Clean, Join, Ext, Walk, Base, IsAbs and Match.
+// A lazybuf is a lazily constructed path buffer.
+// It supports append, reading previously appended bytes,
+// and retrieving the final string. It does not allocate a buffer
+// to hold the output until that buffer diverges from path.
+type lazybuf struct {
+	path       string
+	buf        []byte
+	w          int
+	volAndPath string
+	volLen     int
+}
+
+func (b *lazybuf) index(i int) byte {
+	if b.buf != nil {
+		return b.buf[i]
+	}
+	return b.volAndPath[b.volLen+i]
+}
+
+func (b *lazybuf) append(c byte) {
+	if b.buf == nil {
+		if b.w < len(b.path) && b.path[b.w] == c {
+			b.w++
+			return
+		}
+		b.buf = make([]byte, len(b.path))
+		copy(b.buf, b.path[:b.w])
+	}
+	b.buf[b.w] = c
+	b.w++
+}
+
+func (b *lazybuf) string() string {
+	if b.buf == nil {
+		return b.volAndPath[:b.volLen+b.w]
+	}
+	return b.volAndPath[:b.volLen] + string(b.buf[:b.w])
+}
 
 // Clean returns the shortest path name equivalent to path
 // by purely lexical processing.  It applies the following rules
@@ -27,94 +65,129 @@ import (
 //	   that is, replace "/.." by "/" at the beginning of a path,
 //         assuming Separator is '/'.
 //
+// The returned path ends in a slash only if it represents a root
+// directory, such as "/" on Unix or `C:\` on Windows.
+//
 // If the result of this process is an empty string, Clean
 // returns the string ".".
 //
+// When path is already clean, Clean returns it unchanged without
+// allocating; it only allocates a buffer once the cleaned path
+// diverges from the input.
+//
 // See also Rob Pike, ``Lexical File Names in Plan 9 or
 // Getting Dot-Dot right,''
 // http://plan9.bell-labs.com/sys/doc/lexnames.html
 func Clean(path string) string {
+	originalPath := path
+	volLen := volumeNameLen(path)
+	path = path[volLen:]
 	if path == "" {
-		return "."
+		if volLen > 1 && isSlash(originalPath[0]) && isSlash(originalPath[1]) {
+			// A bare UNC volume, such as `\\host\share`, is already
+			// a clean root; don't append a "." to it.
+			return FromSlash(originalPath)
+		}
+		return originalPath + "."
 	}
+	rooted := isSlash(path[0])
 
-	rooted := path[0] == Separator
-	n := len(path)
+	// Normalize the volume name's separators up front so the fast
+	// path below (the no-alloc case) can compare byte-for-byte
+	// against an already-canonical prefix. A volume name with no '/'
+	// in it is already canonical and is used without a copy.
+	volAndPath := originalPath
+	if strings.Contains(originalPath[:volLen], "/") {
+		vol := []byte(originalPath[:volLen])
+		for i, c := range vol {
+			if isSlash(c) {
+				vol[i] = Separator
+			}
+		}
+		volAndPath = string(vol) + path
+	}
 
 	// Invariants:
 	//	reading from path; r is index of next byte to process.
-	//	writing to buf; w is index of next byte to write.
+	//	writing to out; out.w is index of next byte to write.
 	//	dotdot is index in buf where .. must stop, either because
 	//		it is the leading slash or it is a leading ../../.. prefix.
-	buf := []byte(path)
-	r, w, dotdot := 0, 0, 0
+	n := len(path)
+	out := lazybuf{path: path, volAndPath: volAndPath, volLen: volLen}
+	r, dotdot := 0, 0
 	if rooted {
-		r, w, dotdot = 1, 1, 1
+		out.append(Separator)
+		r, dotdot = 1, 1
 	}
 
 	for r < n {
 		switch {
-		case path[r] == Separator:
+		case isSlash(path[r]):
 			// empty path element
 			r++
-		case path[r] == '.' && (r+1 == n || path[r+1] == Separator):
+		case path[r] == '.' && (r+1 == n || isSlash(path[r+1])):
 			// . element
 			r++
-		case path[r] == '.' && path[r+1] == '.' && (r+2 == n || path[r+2] == Separator):
+		case path[r] == '.' && path[r+1] == '.' && (r+2 == n || isSlash(path[r+2])):
 			// .. element: remove to last separator
 			r += 2
 			switch {
-			case w > dotdot:
+			case out.w > dotdot:
 				// can backtrack
-				w--
-				for w > dotdot && buf[w] != Separator {
-					w--
+				out.w--
+				for out.w > dotdot && !isSlash(out.index(out.w)) {
+					out.w--
 				}
 			case !rooted:
 				// cannot backtrack, but not rooted, so append .. element.
-				if w > 0 {
-					buf[w] = Separator
-					w++
+				if out.w > 0 {
+					out.append(Separator)
 				}
-				buf[w] = '.'
-				w++
-				buf[w] = '.'
-				w++
-				dotdot = w
+				out.append('.')
+				out.append('.')
+				dotdot = out.w
 			}
 		default:
 			// real path element.
 			// add slash if needed
-			if rooted && w != 1 || !rooted && w != 0 {
-				buf[w] = Separator
-				w++
+			if rooted && out.w != 1 || !rooted && out.w != 0 {
+				out.append(Separator)
 			}
 			// copy element
-			for ; r < n && path[r] != Separator; r++ {
-				buf[w] = path[r]
-				w++
+			for ; r < n && !isSlash(path[r]); r++ {
+				out.append(path[r])
 			}
 		}
 	}
 
 	// Turn empty string into "."
-	if w == 0 {
-		buf[w] = '.'
-		w++
+	if out.w == 0 {
+		out.append('.')
 	}
 
-	return string(buf[0:w])
+	return out.string()
+}
+
+// VolumeName returns the leading volume name of path, such as "C:"
+// for `C:\foo` or `\\host\share` for `\\host\share\foo` on Windows.
+// On operating systems with no notion of a volume, it returns "".
+func VolumeName(path string) string {
+	return path[:volumeNameLen(path)]
 }
 
 // Split splits path immediately following the final Separator,
-// partitioning it into a directory and a file name components.
-// In operating systems where VolumeSeparator is not empty and
-// is found in path after any Separator, Split splits the
-// volume name from the file name instead.
-// If there are no separators in path, Split returns an empty base
+// separating it into a directory and file name component.
+// If path has no Separator after its volume name, dir is just the
+// volume name, such as "C:" for `C:foo` on Windows. If path has
+// neither a Separator nor a volume name, Split returns an empty dir
 // and file set to path.
-func Split(path string) (base, file string) {
-	i := strings.LastIndex(path, string(Separator))
+// The returned values have the property that path = dir+file.
+func Split(path string) (dir, file string) {
+	vol := volumeNameLen(path)
+	i := len(path) - 1
+	for i >= vol && !isSlash(path[i]) {
+		i--
+	}
 	return path[:i+1], path[i+1:]
 }
 
@@ -134,7 +207,7 @@ func Join(elem ...string) string {
 // in the final Separator-partitioned element of path;
 // it is empty if there is no dot.
 func Ext(path string) string {
-	for i := len(path) - 1; i >= 0 && path[i] != Separator; i-- {
+	for i := len(path) - 1; i >= 0 && !isSlash(path[i]); i-- {
 		if path[i] == '.' {
 			return path[i:]
 		}
@@ -143,14 +216,26 @@ func Ext(path string) string {
 }
 
 // Visitor methods are invoked for corresponding file tree entries
-// visited by Walk. The parameter path is the full path of f relative
-// to root.
+// visited by WalkVisitor. The parameter path is the full path of f
+// relative to root.
+//
+// Deprecated: use WalkFunc with Walk instead. WalkVisitor will be
+// removed in a future release.
 type Visitor interface {
 	VisitDir(path string, f *os.FileInfo) bool
 	VisitFile(path string, f *os.FileInfo)
 }
 
-func walk(path string, f *os.FileInfo, v Visitor, errors chan<- os.Error) {
+// byName sorts a slice of *os.FileInfo, as returned by ioutil.ReadDir,
+// in the order Walk visits them. Len and Swap are the same on every
+// platform; Less is defined per OS, since Windows compares names
+// case-insensitively.
+type byName []*os.FileInfo
+
+func (f byName) Len() int      { return len(f) }
+func (f byName) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
+
+func walkVisit(path string, f *os.FileInfo, v Visitor, errors chan<- os.Error) {
 	if !f.IsDirectory() {
 		v.VisitFile(path, f)
 		return
@@ -165,21 +250,26 @@ func walk(path string, f *os.FileInfo, v Visitor, errors chan<- os.Error) {
 		if errors != nil {
 			errors <- err
 		}
+	} else {
+		sort.Sort(byName(list))
 	}
 
 	for _, e := range list {
-		walk(Join(path, e.Name), e, v, errors)
+		walkVisit(Join(path, e.Name), e, v, errors)
 	}
 }
 
-// Walk walks the file tree rooted at root, calling v.VisitDir or
+// WalkVisitor walks the file tree rooted at root, calling v.VisitDir or
 // v.VisitFile for each directory or file in the tree, including root.
-// If v.VisitDir returns false, Walk skips the directory's entries;
+// If v.VisitDir returns false, WalkVisitor skips the directory's entries;
 // otherwise it invokes itself for each directory entry in sorted order.
-// An error reading a directory does not abort the Walk.
-// If errors != nil, Walk sends each directory read error
-// to the channel.  Otherwise Walk discards the error.
-func Walk(root string, v Visitor, errors chan<- os.Error) {
+// An error reading a directory does not abort the walk.
+// If errors != nil, WalkVisitor sends each directory read error
+// to the channel.  Otherwise it discards the error.
+//
+// Deprecated: use Walk with a WalkFunc instead. WalkVisitor will be
+// removed in a future release.
+func WalkVisitor(root string, v Visitor, errors chan<- os.Error) {
 	f, err := os.Lstat(root)
 	if err != nil {
 		if errors != nil {
@@ -187,33 +277,360 @@ func Walk(root string, v Visitor, errors chan<- os.Error) {
 		}
 		return // can't progress
 	}
-	walk(root, f, v, errors)
+	walkVisit(root, f, v, errors)
+}
+
+// SkipDir is used as a return value from WalkFuncs to indicate that
+// the directory named in the call is to be skipped. It is not
+// returned as an error by any function.
+var SkipDir = os.NewError("skip this directory")
+
+// WalkFunc is the type of the function called for each file or
+// directory visited by Walk. The path argument contains the argument
+// to Walk as a prefix; that is, if Walk is called with "dir", which is
+// a directory containing the file "a", the walk function will be
+// called with argument "dir/a". The info argument is the os.FileInfo
+// for the named path.
+//
+// If there was a problem walking to the file or directory named by
+// path, the incoming error will describe the problem and the function
+// can decide how to handle that error (and Walk will not descend into
+// that directory). When Walk cannot Lstat the root, the info argument
+// is nil; when it cannot read a directory's entries, info describes
+// that directory. If an error is returned, processing stops. The sole exception
+// is when the function returns SkipDir, which signals Walk to skip
+// the subtree rooted at that directory without aborting the walk.
+type WalkFunc func(path string, info *os.FileInfo, err os.Error) os.Error
+
+func walk(path string, info *os.FileInfo, walkFn WalkFunc) os.Error {
+	err := walkFn(path, info, nil)
+	if !info.IsDirectory() {
+		return err
+	}
+	if err != nil {
+		if err == SkipDir {
+			return nil
+		}
+		return err
+	}
+
+	list, err := ioutil.ReadDir(path)
+	if err != nil {
+		return walkFn(path, info, err)
+	}
+	sort.Sort(byName(list))
+
+	for _, fileInfo := range list {
+		filename := Join(path, fileInfo.Name)
+		if err = walk(filename, fileInfo, walkFn); err != nil {
+			if !fileInfo.IsDirectory() || err != SkipDir {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Walk walks the file tree rooted at root, calling walkFn for each
+// file or directory in the tree, including root. All errors that
+// arise visiting files and directories are filtered by walkFn: see
+// the WalkFunc documentation for details. Directory entries are
+// visited in sorted order.
+func Walk(root string, walkFn WalkFunc) os.Error {
+	f, err := os.Lstat(root)
+	if err != nil {
+		err = walkFn(root, nil, err)
+	} else {
+		err = walk(root, f, walkFn)
+	}
+	if err == SkipDir {
+		return nil
+	}
+	return err
 }
 
-// Base returns the last path element of the Separator-partitioned name.
+// Base returns the last element of path.
 // Trailing Separator elements are removed before extracting the last
-// element.  If the name is empty, "." is returned.  If it consists
-// entirely of Separator elements, a single Separator is returned.
-func Base(name string) string {
-	if name == "" {
+// element.  If the path is empty, Base returns ".".
+// If the path consists entirely of Separator elements, Base returns a
+// single Separator.
+func Base(path string) string {
+	if path == "" {
 		return "."
 	}
 	// Strip trailing slashes.
-	for len(name) > 0 && name[len(name)-1] == Separator {
-		name = name[0 : len(name)-1]
+	for len(path) > 0 && isSlash(path[len(path)-1]) {
+		path = path[0 : len(path)-1]
 	}
+	// Throw away volume name
+	path = path[len(VolumeName(path)):]
 	// Find the last element
-	if i := strings.LastIndex(name, string(Separator)); i >= 0 {
-		name = name[i+1:]
+	i := len(path) - 1
+	for i >= 0 && !isSlash(path[i]) {
+		i--
+	}
+	if i >= 0 {
+		path = path[i+1:]
 	}
 	// If empty now, it had only slashes.
-	if name == "" {
+	if path == "" {
 		return string(Separator)
 	}
-	return name
+	return path
 }
 
 // IsAbs returns true if the path is absolute.
+// On Windows a path is absolute only if it has both a volume and a
+// rooted body, such as `C:\foo`; `C:foo` (no separator after the
+// volume) and `\foo` (no volume) are both relative.
 func IsAbs(path string) bool {
-	return len(path) > 0 && path[0] == Separator
+	return isAbs(path)
+}
+
+// Abs returns an absolute representation of path. If the path is not
+// absolute it will be joined with the current working directory to
+// turn it into an absolute path. The absolute path name for a given
+// file is not guaranteed to be unique.
+func Abs(path string) (string, os.Error) {
+	if IsAbs(path) {
+		return Clean(path), nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return Join(wd, path), nil
+}
+
+// Rel returns a relative path that is lexically equivalent to targpath
+// when joined to basepath with an intervening separator. That is,
+// Join(basepath, Rel(basepath, targpath)) is equivalent to targpath
+// itself. On success, the returned path will always be relative to
+// basepath, even if basepath and targpath share no elements. An error
+// is returned if targpath can't be made relative to basepath, or if
+// knowing the current working directory would be necessary to compute
+// it, or if the two paths are rooted on different volumes.
+func Rel(basepath, targpath string) (string, os.Error) {
+	baseVol := VolumeName(basepath)
+	targVol := VolumeName(targpath)
+	base := Clean(basepath)
+	targ := Clean(targpath)
+	if targ == base {
+		return ".", nil
+	}
+	base = base[len(baseVol):]
+	targ = targ[len(targVol):]
+	if base == "." {
+		base = ""
+	}
+	// Can't use IsAbs: on Windows `\a` and `a` are both relative.
+	baseSlashed := len(base) > 0 && base[0] == Separator
+	targSlashed := len(targ) > 0 && targ[0] == Separator
+	if baseSlashed != targSlashed || strings.ToUpper(baseVol) != strings.ToUpper(targVol) {
+		return "", os.NewError("Rel: can't make " + targpath + " relative to " + basepath)
+	}
+	// Position base[b0:bi] and targ[t0:ti] at the first differing elements.
+	bl := len(base)
+	tl := len(targ)
+	var b0, bi, t0, ti int
+	for {
+		for bi < bl && base[bi] != Separator {
+			bi++
+		}
+		for ti < tl && targ[ti] != Separator {
+			ti++
+		}
+		if targ[t0:ti] != base[b0:bi] {
+			break
+		}
+		if bi < bl {
+			bi++
+		}
+		if ti < tl {
+			ti++
+		}
+		b0 = bi
+		t0 = ti
+	}
+	if base[b0:bi] == ".." {
+		return "", os.NewError("Rel: can't make " + targpath + " relative to " + basepath)
+	}
+	if b0 != bl {
+		// Base elements left. Must go up before going down.
+		seps := strings.Count(base[b0:bl], string(Separator))
+		size := 2 + seps*3
+		if tl != t0 {
+			size += 1 + tl - t0
+		}
+		buf := make([]byte, size)
+		n := copy(buf, "..")
+		for i := 0; i < seps; i++ {
+			buf[n] = Separator
+			copy(buf[n+1:], "..")
+			n += 3
+		}
+		if t0 != tl {
+			buf[n] = Separator
+			copy(buf[n+1:], targ[t0:])
+		}
+		return string(buf), nil
+	}
+	return targ[t0:], nil
+}
+
+// indexSlash returns the index of the first path separator in s, or
+// -1 if there is none.
+func indexSlash(s string) int {
+	for i := 0; i < len(s); i++ {
+		if isSlash(s[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastIndexSlash returns the index of the last path separator in
+// s[min:], or -1 if there is none.
+func lastIndexSlash(s string, min int) int {
+	for i := len(s) - 1; i >= min; i-- {
+		if isSlash(s[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// EvalSymlinks returns the path name after the evaluation of any
+// symbolic links. If path is relative it will be evaluated relative
+// to the current directory. EvalSymlinks calls Clean on the result.
+//
+// It resolves path one component at a time, following at most 255
+// symbolic links; beyond that it assumes the links form a cycle and
+// gives up.
+func EvalSymlinks(path string) (string, os.Error) {
+	const maxLinks = 255
+
+	volLen := volumeNameLen(path)
+	if volLen < len(path) && isSlash(path[volLen]) {
+		// Keep the root separator with the volume so dest starts
+		// rooted for absolute paths instead of losing it the first
+		// time src's leading separators are skipped below.
+		volLen++
+	}
+	vol := path[:volLen]
+	dest := vol
+	linksWalked := 0
+	src := path[volLen:]
+
+	for len(src) > 0 {
+		for len(src) > 0 && isSlash(src[0]) {
+			src = src[1:]
+		}
+		var component string
+		if i := indexSlash(src); i < 0 {
+			component, src = src, ""
+		} else {
+			component, src = src[:i], src[i:]
+		}
+		if component == "" || component == "." {
+			continue
+		}
+		if component == ".." {
+			i := lastIndexSlash(dest, len(vol))
+			start := len(vol)
+			if i >= 0 {
+				start = i + 1
+			}
+			if dest[start:] == ".." || dest == vol && (vol == "" || len(vol) == 2 && vol[1] == ':') {
+				// A relative path with no element left to back out
+				// of keeps the "..".
+				if dest[start:] == ".." {
+					dest += string(Separator)
+				}
+				dest += ".."
+			} else if i >= 0 {
+				dest = dest[:i]
+			} else {
+				dest = vol
+			}
+			continue
+		}
+
+		next := dest
+		if next != "" && !isSlash(next[len(next)-1]) {
+			next += string(Separator)
+		}
+		next += component
+
+		fi, err := os.Lstat(next)
+		if err != nil {
+			return "", err
+		}
+		if !fi.IsSymlink() {
+			dest = next
+			continue
+		}
+
+		linksWalked++
+		if linksWalked > maxLinks {
+			return "", os.NewError("EvalSymlinks: too many links in " + path)
+		}
+
+		link, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+
+		if len(link) > 0 && (isAbs(link) || isSlash(link[0])) {
+			linkVolLen := volumeNameLen(link)
+			if linkVolLen < len(link) && isSlash(link[linkVolLen]) {
+				linkVolLen++
+			}
+			vol = link[:linkVolLen]
+			dest = vol
+			src = link[linkVolLen:] + src
+		} else {
+			src = link + src
+		}
+	}
+	return Clean(dest), nil
+}
+
+// ToSlash returns the result of replacing each separator character
+// in path with a slash ('/') character. Multiple separators are
+// replaced by multiple slashes.
+func ToSlash(path string) string {
+	if Separator == '/' {
+		return path
+	}
+	return strings.Map(func(r int) int {
+		if r == Separator {
+			return '/'
+		}
+		return r
+	}, path)
+}
+
+// FromSlash returns the result of replacing each slash ('/') character
+// in path with a separator character. Multiple slashes are replaced
+// by multiple separators.
+func FromSlash(path string) string {
+	if Separator == '/' {
+		return path
+	}
+	return strings.Map(func(r int) int {
+		if r == '/' {
+			return Separator
+		}
+		return r
+	}, path)
+}
+
+// SplitList splits a list of paths joined by the OS-specific
+// ListSeparator, such as the PATH or GOPATH environment variables.
+func SplitList(path string) []string {
+	if path == "" {
+		return []string{}
+	}
+	return strings.Split(path, string(ListSeparator))
 }
@@ -0,0 +1,374 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filepath_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type RelTest struct {
+	base, targ, want string
+}
+
+var reltests = []RelTest{
+	{"a/b", "a/b", "."},
+	{"a/b/.", "a/b", "."},
+	{"a/b", "a/b/.", "."},
+	{"ab/cd", "ab/cde", "../cde"},
+	{"ab/cd", "ab/cd/ef", "ef"},
+	{"ab/cd", "ab/cd/ef/g/h", "ef/g/h"},
+	{"ab/cd", "ab/ef/g/h", "../ef/g/h"},
+	{"ab/cd", "bc", "../../bc"},
+	{"a/b/c/d", "a/b/d/e", "../../d/e"},
+	{"a/b/c/d", "a/b/c", ".."},
+	{"a/b/c", "a/b/c/d", "d"},
+	{"a/b", "c/d", "../../c/d"},
+	{"/a/b", "/a/b", "."},
+	{"/a/b/c/d", "/a/b/d/e", "../../d/e"},
+	{".", "a/b", "a/b"},
+	{".", "..", ".."},
+}
+
+var relerrtests = []RelTest{
+	{"..", ".", ""},
+	{"..", "a", ""},
+	{"../..", "..", ""},
+	{"a", "/a", ""},
+	{"/a", "a", ""},
+}
+
+var cleantests = []struct {
+	path, result string
+}{
+	{"", "."},
+	{"abc", "abc"},
+	{"abc/def", "abc/def"},
+	{"a/b/c", "a/b/c"},
+	{".", "."},
+	{"..", ".."},
+	{"../..", "../.."},
+	{"../../abc", "../../abc"},
+	{"/abc", "/abc"},
+	{"/", "/"},
+	{"abc/./def", "abc/def"},
+	{"/./abc/def", "/abc/def"},
+	{"abc/..", "."},
+	{"abc/def/..", "abc"},
+	{"abc/def/../..", "."},
+	{"/abc/def/../..", "/"},
+	{"/abc/../../..", "/"},
+	{"abc//def//ghi", "abc/def/ghi"},
+	{"//abc", "/abc"},
+	{"///abc", "/abc"},
+	{"//abc//", "/abc"},
+}
+
+var splittests = []struct {
+	path, dir, file string
+}{
+	{"a/b", "a/", "b"},
+	{"a/b/", "a/b/", ""},
+	{"a", "", "a"},
+	{"/", "/", ""},
+}
+
+var jointests = []struct {
+	elem []string
+	path string
+}{
+	{[]string{}, ""},
+	{[]string{""}, ""},
+	{[]string{"a"}, "a"},
+	{[]string{"a", "b"}, "a/b"},
+	{[]string{"a", ""}, "a"},
+	{[]string{"", "b"}, "b"},
+	{[]string{"/", "a"}, "/a"},
+	{[]string{"a/", "b"}, "a/b"},
+	{[]string{"a/", ""}, "a"},
+	{[]string{"", ""}, ""},
+}
+
+var basetests = []struct {
+	path, result string
+}{
+	{"", "."},
+	{".", "."},
+	{"/.", "."},
+	{"/", "/"},
+	{"////", "/"},
+	{"x/", "x"},
+	{"abc", "abc"},
+	{"abc/def", "def"},
+	{"a/b/.x", ".x"},
+}
+
+func init() {
+	// Clean, Join, Rel and Split report native separators, so the
+	// tables above, written with slashes, only hold on Windows once
+	// translated.
+	if runtime.GOOS == "windows" {
+		for i := range reltests {
+			reltests[i].want = filepath.FromSlash(reltests[i].want)
+		}
+		for i := range cleantests {
+			cleantests[i].path = filepath.FromSlash(cleantests[i].path)
+			cleantests[i].result = filepath.FromSlash(cleantests[i].result)
+		}
+		for i := range splittests {
+			splittests[i].path = filepath.FromSlash(splittests[i].path)
+			splittests[i].dir = filepath.FromSlash(splittests[i].dir)
+			splittests[i].file = filepath.FromSlash(splittests[i].file)
+		}
+		for i := range jointests {
+			for j := range jointests[i].elem {
+				jointests[i].elem[j] = filepath.FromSlash(jointests[i].elem[j])
+			}
+			jointests[i].path = filepath.FromSlash(jointests[i].path)
+		}
+		for i := range basetests {
+			basetests[i].path = filepath.FromSlash(basetests[i].path)
+			basetests[i].result = filepath.FromSlash(basetests[i].result)
+		}
+	}
+}
+
+func TestClean(t *testing.T) {
+	for _, test := range cleantests {
+		if s := filepath.Clean(test.path); s != test.result {
+			t.Errorf("Clean(%q) = %q, want %q", test.path, s, test.result)
+		}
+		if s := filepath.Clean(test.result); s != test.result {
+			t.Errorf("Clean(%q) = %q, want %q", test.result, s, test.result)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	for _, test := range splittests {
+		if d, f := filepath.Split(test.path); d != test.dir || f != test.file {
+			t.Errorf("Split(%q) = %q, %q, want %q, %q", test.path, d, f, test.dir, test.file)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	for _, test := range jointests {
+		if p := filepath.Join(test.elem...); p != test.path {
+			t.Errorf("Join(%q) = %q, want %q", test.elem, p, test.path)
+		}
+	}
+}
+
+func TestBase(t *testing.T) {
+	for _, test := range basetests {
+		if s := filepath.Base(test.path); s != test.result {
+			t.Errorf("Base(%q) = %q, want %q", test.path, s, test.result)
+		}
+	}
+}
+
+func TestRel(t *testing.T) {
+	for _, test := range reltests {
+		got, err := filepath.Rel(test.base, test.targ)
+		if err != nil {
+			t.Errorf("Rel(%q, %q) returned error %v", test.base, test.targ, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Rel(%q, %q) = %q, want %q", test.base, test.targ, got, test.want)
+		}
+	}
+	for _, test := range relerrtests {
+		if _, err := filepath.Rel(test.base, test.targ); err == nil {
+			t.Errorf("Rel(%q, %q) succeeded, want error", test.base, test.targ)
+		}
+	}
+}
+
+func TestEvalSymlinks(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "TestEvalSymlinks")
+	if err != nil {
+		t.Fatal("TempDir: ", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dir := filepath.Join(tmpDir, "dir")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal("Mkdir: ", err)
+	}
+	file := filepath.Join(dir, "file")
+	if f, err := os.Create(file); err != nil {
+		t.Fatal("Create: ", err)
+	} else {
+		f.Close()
+	}
+	link := filepath.Join(tmpDir, "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Fatal("Symlink: ", err)
+	}
+
+	// The case that broke: resolving a rooted path must not drop the
+	// leading separator, even when it has no symlinks to resolve.
+	got, err := filepath.EvalSymlinks(file)
+	if err != nil {
+		t.Fatal("EvalSymlinks: ", err)
+	}
+	if want := filepath.Clean(file); got != want {
+		t.Errorf("EvalSymlinks(%q) = %q, want %q", file, got, want)
+	}
+
+	got, err = filepath.EvalSymlinks(filepath.Join(link, "file"))
+	if err != nil {
+		t.Fatal("EvalSymlinks: ", err)
+	}
+	if want := filepath.Clean(file); got != want {
+		t.Errorf("EvalSymlinks(%q) = %q, want %q", filepath.Join(link, "file"), got, want)
+	}
+}
+
+// makeWalkTree builds a fixed tree under dir so TestWalk has something
+// with nested directories and files to traverse:
+//
+//	dir/a
+//	dir/b/c
+//	dir/skip/d
+func makeWalkTree(t *testing.T, dir string) {
+	if err := os.Mkdir(filepath.Join(dir, "b"), 0700); err != nil {
+		t.Fatal("Mkdir: ", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "skip"), 0700); err != nil {
+		t.Fatal("Mkdir: ", err)
+	}
+	for _, name := range []string{"a", "b/c", "skip/d"} {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal("Create: ", err)
+		}
+		f.Close()
+	}
+}
+
+func TestWalk(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "TestWalk")
+	if err != nil {
+		t.Fatal("TempDir: ", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	makeWalkTree(t, tmpDir)
+
+	var visited []string
+	err = filepath.Walk(tmpDir, func(path string, info *os.FileInfo, err os.Error) os.Error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path[len(tmpDir):])
+		if info.IsDirectory() && filepath.Base(path) == "skip" {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal("Walk: ", err)
+	}
+	want := []string{"", "/a", "/b", "/b/c", "/skip"}
+	if len(visited) != len(want) {
+		t.Fatalf("Walk visited %v, want %v", visited, want)
+	}
+	for i, w := range want {
+		if visited[i] != w {
+			t.Errorf("Walk visited[%d] = %q, want %q (visited=%v)", i, visited[i], w, visited)
+		}
+	}
+
+	// A non-SkipDir error returned by walkFn must abort the walk.
+	abort := os.NewError("stop")
+	calls := 0
+	err = filepath.Walk(tmpDir, func(path string, info *os.FileInfo, err os.Error) os.Error {
+		calls++
+		if filepath.Base(path) == "b" {
+			return abort
+		}
+		return nil
+	})
+	if err != abort {
+		t.Errorf("Walk returned %v, want %v", err, abort)
+	}
+	if calls == 0 {
+		t.Errorf("Walk never called walkFn")
+	}
+}
+
+func TestAbs(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal("Getwd: ", err)
+	}
+
+	if got, err := filepath.Abs("foo"); err != nil {
+		t.Errorf("Abs(%q) returned error %v", "foo", err)
+	} else if want := filepath.Join(wd, "foo"); got != want {
+		t.Errorf("Abs(%q) = %q, want %q", "foo", got, want)
+	}
+
+	abs := filepath.Join(wd, "bar")
+	if got, err := filepath.Abs(abs); err != nil {
+		t.Errorf("Abs(%q) returned error %v", abs, err)
+	} else if got != abs {
+		t.Errorf("Abs(%q) = %q, want %q", abs, got, abs)
+	}
+}
+
+func TestToSlashFromSlash(t *testing.T) {
+	slashed := "a/b/c"
+	native := strings.Replace(slashed, "/", string(filepath.Separator), -1)
+
+	if got := filepath.FromSlash(slashed); got != native {
+		t.Errorf("FromSlash(%q) = %q, want %q", slashed, got, native)
+	}
+	if got := filepath.ToSlash(native); got != slashed {
+		t.Errorf("ToSlash(%q) = %q, want %q", native, got, slashed)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	sep := string(filepath.ListSeparator)
+	joined := strings.Join([]string{"a", "b", "c"}, sep)
+	got := filepath.SplitList(joined)
+	want := []string{"a", "b", "c"}
+	if len(got) != len(want) {
+		t.Fatalf("SplitList(%q) = %v, want %v", joined, got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("SplitList(%q)[%d] = %q, want %q", joined, i, got[i], want[i])
+		}
+	}
+
+	if got := filepath.SplitList(""); len(got) != 0 {
+		t.Errorf("SplitList(\"\") = %v, want empty", got)
+	}
+}
+
+// BenchmarkClean measures Clean on a path that already contains a
+// ".." element, the common case that still requires rewriting.
+func BenchmarkClean(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		filepath.Clean("/a/b/c/../d/e/f")
+	}
+}
+
+// BenchmarkCleanAlreadyClean measures Clean on an input that is
+// already in canonical form, the case the lazybuf fast path is
+// meant to make allocation-free.
+func BenchmarkCleanAlreadyClean(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		filepath.Clean("/a/b/c/d/e/f")
+	}
+}
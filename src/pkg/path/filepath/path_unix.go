@@ -0,0 +1,32 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !windows
+
+package filepath
+
+const (
+	Separator       = '/' // OS-specific path separator
+	VolumeSeparator = 0   // OS-specific volume separator; zero means none
+	ListSeparator   = ':' // OS-specific path list separator
+)
+
+// isSlash reports whether c is a path separator on this platform.
+func isSlash(c uint8) bool {
+	return c == Separator
+}
+
+// volumeNameLen returns the length of the leading volume name in path.
+// Unix paths have no notion of a volume, so it always returns 0.
+func volumeNameLen(path string) int {
+	return 0
+}
+
+// isAbs reports whether path is rooted at the filesystem root.
+func isAbs(path string) bool {
+	return len(path) > 0 && path[0] == Separator
+}
+
+// Less compares directory entries by name, byte for byte.
+func (f byName) Less(i, j int) bool { return f[i].Name < f[j].Name }
@@ -2,9 +2,91 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
+// +build windows
+
 package filepath
 
+import "strings"
+
 const (
-	Separator       = `\` // OS-specific path separator
-	VolumeSeparator = `:` // OS-specific volume separator (empty in most OSes)
+	Separator       = '\\' // OS-specific path separator
+	VolumeSeparator = ':'  // OS-specific volume separator
+	ListSeparator   = ';'  // OS-specific path list separator
 )
+
+// isSlash reports whether c is a path separator on this platform.
+// Windows accepts both \ and / as separators.
+func isSlash(c uint8) bool {
+	return c == '\\' || c == '/'
+}
+
+// volumeNameLen returns the length of the leading volume name in path.
+// It recognizes drive letters (`C:`), UNC shares (`\\host\share`) and
+// device namespace paths (`\\.\` and `\\?\`). It returns 0 if path has
+// no volume name.
+func volumeNameLen(path string) int {
+	if len(path) < 2 {
+		return 0
+	}
+	// with drive letter
+	c := path[0]
+	if path[1] == ':' && ('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+		return 2
+	}
+	l := len(path)
+	// device namespace, like \\.\COM1 or \\?\C:\foo
+	if l >= 4 && isSlash(path[0]) && isSlash(path[1]) && (path[2] == '.' || path[2] == '?') && isSlash(path[3]) {
+		for n := 4; n < l; n++ {
+			if isSlash(path[n]) {
+				return n
+			}
+		}
+		return l
+	}
+	// is it UNC? \\server\share
+	if l >= 5 && isSlash(path[0]) && isSlash(path[1]) && !isSlash(path[2]) && path[2] != '.' {
+		// first, leading `\\` and next shouldn't be `\`; it's the server name.
+		for n := 3; n < l-1; n++ {
+			// second, next '\' shouldn't be repeated.
+			if isSlash(path[n]) {
+				n++
+				// third, find another '\'; we're in the share name.
+				if !isSlash(path[n]) {
+					if path[n] == '.' {
+						break
+					}
+					for ; n < l; n++ {
+						if isSlash(path[n]) {
+							break
+						}
+					}
+					return n
+				}
+				break
+			}
+		}
+	}
+	return 0
+}
+
+// isAbs reports whether path is rooted: it must carry a volume name
+// and a separator immediately following it, e.g. `C:\foo`.
+// `C:foo` (volume but no separator) and `\foo` (separator but no
+// volume) are both relative.
+func isAbs(path string) bool {
+	l := volumeNameLen(path)
+	if l == 0 {
+		return false
+	}
+	path = path[l:]
+	if path == "" {
+		return false
+	}
+	return isSlash(path[0])
+}
+
+// Less compares directory entries by name, ignoring case, matching
+// the case-insensitivity of the Windows filesystem.
+func (f byName) Less(i, j int) bool {
+	return strings.ToLower(f[i].Name) < strings.ToLower(f[j].Name)
+}
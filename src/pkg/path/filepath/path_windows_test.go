@@ -0,0 +1,130 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package filepath_test
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+type PathTest struct {
+	path, result string
+}
+
+var winclean = []PathTest{
+	{`C:\`, `C:\`},
+	{`C:\abc`, `C:\abc`},
+	{`C:\abc\..\abc`, `C:\abc`},
+	{`C:\..\abc`, `C:\abc`},
+	{`C:..\abc`, `C:..\abc`},
+	{`C:`, `C:.`},
+	{`\\host\share`, `\\host\share`},
+	{`\\host\share\`, `\\host\share\`},
+	{`\\host\share\abc`, `\\host\share\abc`},
+	{`\\host\share\..\abc`, `\\host\share\abc`},
+	{`//host/share/abc/../abc`, `\\host\share\abc`},
+	{`\`, `\`},
+	{`/`, `\`},
+	{`a/b`, `a\b`},
+	{`a\b`, `a\b`},
+	{`a/b/../c`, `a\c`},
+}
+
+func TestWinCleanPath(t *testing.T) {
+	for _, test := range winclean {
+		if s := filepath.Clean(test.path); s != test.result {
+			t.Errorf("Clean(%q) = %q, want %q", test.path, s, test.result)
+		}
+	}
+}
+
+type IsAbsTest struct {
+	path  string
+	isAbs bool
+}
+
+var isabstests = []IsAbsTest{
+	{`C:\`, true},
+	{`C:\foo`, true},
+	{`C:foo`, false},
+	{`\foo`, false},
+	{`\\host\share`, false},
+	{`\\host\share\`, true},
+	{`\\host\share\foo`, true},
+	{`foo`, false},
+	{``, false},
+}
+
+func TestWinIsAbs(t *testing.T) {
+	for _, test := range isabstests {
+		if r := filepath.IsAbs(test.path); r != test.isAbs {
+			t.Errorf("IsAbs(%q) = %v, want %v", test.path, r, test.isAbs)
+		}
+	}
+}
+
+type VolumeNameTest struct {
+	path string
+	vol  string
+}
+
+var volumenametests = []VolumeNameTest{
+	{`C:\foo\bar`, `C:`},
+	{`c:foo`, `c:`},
+	{`\\host\share\foo`, `\\host\share`},
+	{`\\host\share`, `\\host\share`},
+	{`\foo`, ``},
+	{`foo`, ``},
+}
+
+func TestWinVolumeName(t *testing.T) {
+	for _, test := range volumenametests {
+		if v := filepath.VolumeName(test.path); v != test.vol {
+			t.Errorf("VolumeName(%q) = %q, want %q", test.path, v, test.vol)
+		}
+	}
+}
+
+type SplitTest struct {
+	path, dir, file string
+}
+
+var winsplittests = []SplitTest{
+	{`C:\foo\bar`, `C:\foo\`, `bar`},
+	{`C:\foo\`, `C:\foo\`, ``},
+	{`C:foo`, `C:`, `foo`},
+	{`\\host\share\foo`, `\\host\share\`, `foo`},
+}
+
+func TestWinSplit(t *testing.T) {
+	for _, test := range winsplittests {
+		if d, f := filepath.Split(test.path); d != test.dir || f != test.file {
+			t.Errorf("Split(%q) = %q, %q, want %q, %q", test.path, d, f, test.dir, test.file)
+		}
+	}
+}
+
+type BaseTest struct {
+	path, result string
+}
+
+var winbasetests = []BaseTest{
+	{`C:\foo\bar`, `bar`},
+	{`C:\foo\`, `foo`},
+	{`C:\`, `\`},
+	{`C:.`, `.`},
+	{`\\host\share\foo`, `foo`},
+	{`\\host\share\`, `\`},
+}
+
+func TestWinBase(t *testing.T) {
+	for _, test := range winbasetests {
+		if s := filepath.Base(test.path); s != test.result {
+			t.Errorf("Base(%q) = %q, want %q", test.path, s, test.result)
+		}
+	}
+}